@@ -1,6 +1,8 @@
 package bank
 
-// Define phantom type markers
+import "errors"
+
+// Define phantom type markers for the account lifecycle.
 type active struct{}
 type closed struct{}
 type pending struct{}
@@ -13,9 +15,25 @@ type pendingOrClosed interface {
 	pending | closed
 }
 
-type Account[State state] struct {
+// Define phantom type markers for the account kind. This is a second,
+// orthogonal axis: every lifecycle state above combines with every kind
+// below, e.g. Account[active, margin] or Account[closed, isolated].
+type standard struct{}
+type margin struct{}
+type isolated struct{}
+
+type kind interface {
+	standard | margin | isolated
+}
+
+type marginOrIsolated interface {
+	margin | isolated
+}
+
+type Account[State state, Kind kind] struct {
 	ID      string
 	Balance float64
+	Debt    float64 // only meaningful for Margin/Isolated accounts
 
 	// we could add flags or metadata here
 }
@@ -24,42 +42,57 @@ type Account[State state] struct {
 // private method forces type-specific implementation
 // ==================================================
 
-// CanDeposit enforces that only certain states can deposit
-type CanDeposit[T active] interface {
-	*Account[T]
+// CanDeposit enforces that only certain states can deposit. Kind-agnostic:
+// any account kind may deposit so long as it is active.
+type CanDeposit[T active, K kind] interface {
+	*Account[T, K]
 	deposit(amount float64)
 }
 
-// CanWithdraw enforces that only certain states can withdraw
-type CanWithdraw[T active] interface {
-	*Account[T]
+// CanWithdraw enforces that only certain states can withdraw. Kind-agnostic.
+type CanWithdraw[T active, K kind] interface {
+	*Account[T, K]
 	withdraw(amount float64) bool
 }
 
-// CanClose enforces that only certain states can close
-type CanClose[T active] interface {
-	*Account[T]
-	close() *Account[closed]
+// CanClose enforces that only certain states can close. Kind-agnostic.
+type CanClose[T active, K kind] interface {
+	*Account[T, K]
+	close() *Account[closed, K]
 }
 
 // Intreface composition
-type CanWithdrawAndClose[T active] interface {
-	CanWithdraw[T]
-	CanClose[T]
+type CanWithdrawAndClose[T active, K kind] interface {
+	CanWithdraw[T, K]
+	CanClose[T, K]
+}
+
+type CanActivate[T pendingOrClosed, K kind] interface {
+	*Account[T, K]
+	activate() *Account[active, K]
+}
+
+// CanBorrow gates Borrow to active Margin or Isolated accounts; a Standard
+// account never satisfies this constraint.
+type CanBorrow[T active, K marginOrIsolated] interface {
+	*Account[T, K]
+	borrow(amount float64)
 }
 
-type CanActivate[T pendingOrClosed] interface {
-	*Account[T]
-	activate() *Account[active]
+// CanTransferCollateral gates TransferCollateral to active Isolated
+// accounts only.
+type CanTransferCollateral[T active, K isolated] interface {
+	*Account[T, K]
+	transferCollateral(amount float64) error
 }
 
 // Only ActiveAccount can deposit.
-func (a *Account[ActiveState]) deposit(amount float64) {
+func (a *Account[ActiveState, Kind]) deposit(amount float64) {
 	a.Balance += amount
 }
 
 // Only ActiveAccount can withdraw.
-func (a *Account[ActiveState]) withdraw(amount float64) bool {
+func (a *Account[ActiveState, Kind]) withdraw(amount float64) bool {
 	if a.Balance >= amount {
 		a.Balance -= amount
 		return true
@@ -68,69 +101,142 @@ func (a *Account[ActiveState]) withdraw(amount float64) bool {
 }
 
 // Only ActiveAccount can close.
-func (a *Account[ActiveState]) close() *Account[closed] {
-	return &Account[closed]{
+func (a *Account[ActiveState, Kind]) close() *Account[closed, Kind] {
+	return &Account[closed, Kind]{
 		ID:      a.ID,
 		Balance: a.Balance,
+		Debt:    a.Debt,
 	}
 }
 
+// Only a Margin or Isolated account can borrow.
+func (a *Account[ActiveState, Kind]) borrow(amount float64) {
+	a.Debt += amount
+	a.Balance += amount
+}
+
+// Only an Isolated account can transfer collateral out of the account.
+func (a *Account[ActiveState, Kind]) transferCollateral(amount float64) error {
+	if amount > a.Balance {
+		return errors.New("bank: insufficient balance to transfer collateral")
+	}
+	a.Balance -= amount
+	a.Debt += amount
+	return nil
+}
+
 // Public API functions with type constraints.
 // Deposit, Withdraw, and Close can only be called on accounts in the correct state.
-func Deposit[T active, A CanDeposit[T]](acc A, amount float64) {
+func Deposit[T active, K kind, A CanDeposit[T, K]](acc A, amount float64) {
 	acc.deposit(amount)
 }
 
 // Withdraw returns true if successful, false if insufficient funds.
-func Withdraw[T active, A CanWithdraw[T]](acc A, amount float64) bool {
+func Withdraw[T active, K kind, A CanWithdraw[T, K]](acc A, amount float64) bool {
 	return acc.withdraw(amount)
 }
 
-func WithdrawAndClose[T active, A CanWithdrawAndClose[T]](acc A, amount float64) *Account[closed] {
+func WithdrawAndClose[T active, K kind, A CanWithdrawAndClose[T, K]](acc A, amount float64) *Account[closed, K] {
 	acc.withdraw(amount)
 	return acc.close()
 }
 
 // Close transitions an active account to a closed account.
-func Close[T active, A CanClose[T]](acc A) *Account[closed] {
+func Close[T active, K kind, A CanClose[T, K]](acc A) *Account[closed, K] {
 	return acc.close()
 }
 
+// Borrow draws down amount against a Margin or Isolated account's debt line.
+func Borrow[T active, K marginOrIsolated, A CanBorrow[T, K]](acc A, amount float64) {
+	acc.borrow(amount)
+}
+
+// TransferCollateral moves amount out of an Isolated account's balance and
+// into its isolated debt exposure.
+func TransferCollateral[T active, K isolated, A CanTransferCollateral[T, K]](acc A, amount float64) error {
+	return acc.transferCollateral(amount)
+}
+
 // Universal operations (available on all account types)
-func (a *Account[AccountState]) GetBalance() float64 {
+func (a *Account[AccountState, Kind]) GetBalance() float64 {
 	return a.Balance
 }
 
 // GetID returns the account ID.
-func (a *Account[AccountState]) GetID() string {
+func (a *Account[AccountState, Kind]) GetID() string {
 	return a.ID
 }
 
-func (a *Account[PendingState]) activate() *Account[active] {
-	return &Account[active]{
+// GetDebt returns the account's outstanding debt (zero for Standard accounts).
+func (a *Account[AccountState, Kind]) GetDebt() float64 {
+	return a.Debt
+}
+
+func (a *Account[PendingState, Kind]) activate() *Account[active, Kind] {
+	return &Account[active, Kind]{
 		ID:      a.ID,
 		Balance: a.Balance,
+		Debt:    a.Debt,
 	}
 }
 
 // Activate transitions a pending or closed account to an active account.
-func Activate[T pendingOrClosed, A CanActivate[T]](acc A) *Account[active] {
+func Activate[T pendingOrClosed, K kind, A CanActivate[T, K]](acc A) *Account[active, K] {
 	return acc.activate()
 }
 
 // Type specializations for Activate for Pending account.
 // If we don't specialize, then the caller MUST specify the type parameter explicitly.
 // But we may want to keep the state private, so we provide these helpers.
-func ActivatePending(acc *Account[pending]) *Account[active] {
+func ActivatePending[K kind](acc *Account[pending, K]) *Account[active, K] {
 	return acc.activate()
 }
 
 // Type specializations for Activate for Closed account.
-func ActivateClosed(acc *Account[closed]) *Account[active] {
+func ActivateClosed[K kind](acc *Account[closed, K]) *Account[active, K] {
 	return acc.activate()
 }
 
+// Constructors, one per Kind, mirroring how Account is normally opened.
+func NewStandard(id string, balance float64) *Account[active, standard] {
+	return &Account[active, standard]{ID: id, Balance: balance}
+}
+
+func NewMargin(id string, balance float64) *Account[active, margin] {
+	return &Account[active, margin]{ID: id, Balance: balance}
+}
+
+func NewIsolated(id string, balance float64) *Account[active, isolated] {
+	return &Account[active, isolated]{ID: id, Balance: balance}
+}
+
+// PromoteToMargin converts a Standard account to a Margin account, carrying
+// its balance across. The kind transition is visible in the return type,
+// just like Activate/Close transition the lifecycle state.
+func PromoteToMargin(acc *Account[active, standard]) (*Account[active, margin], error) {
+	if acc.Debt != 0 {
+		return nil, errors.New("bank: cannot promote an account with outstanding debt")
+	}
+	return &Account[active, margin]{ID: acc.ID, Balance: acc.Balance}, nil
+}
+
+// PromoteToIsolated converts a Standard account to an Isolated account,
+// carrying its balance across.
+func PromoteToIsolated(acc *Account[active, standard]) (*Account[active, isolated], error) {
+	if acc.Debt != 0 {
+		return nil, errors.New("bank: cannot promote an account with outstanding debt")
+	}
+	return &Account[active, isolated]{ID: acc.ID, Balance: acc.Balance}, nil
+}
+
 // Helpers
-type ActiveAccount = Account[active]
-type PendingAccount = Account[pending]
-type ClosedAccount = Account[closed]
+type ActiveAccount = Account[active, standard]
+type PendingAccount = Account[pending, standard]
+type ClosedAccount = Account[closed, standard]
+
+type MarginAccount = Account[active, margin]
+type IsolatedAccount = Account[active, isolated]
+
+// This operation would cause a compile-time error:
+//
+//	Borrow(NewStandard("ACC-1", 100), 50) // Error: standard doesn't satisfy CanBorrow's K marginOrIsolated
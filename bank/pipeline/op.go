@@ -0,0 +1,119 @@
+package pipeline
+
+import "github.com/abiiranathan/golang-type-state-pattern/bank"
+
+// Phantom markers mirroring bank's lifecycle states, so an Op's type
+// parameter records which Worker it belongs on.
+type active struct{}
+type pending struct{}
+type closed struct{}
+
+type state interface {
+	active | pending | closed
+}
+
+// Op is a sealed operation targeting an account in state S. sealed() being
+// unexported means only this package can implement Op.
+type Op[S state] interface {
+	apply() Result
+	sealed()
+}
+
+// Result is the outcome of applying an Op: the target account's new ID,
+// balance and state, or a non-nil Err if the operation failed.
+type Result struct {
+	ID      string
+	Balance float64
+	State   string
+	Err     error
+}
+
+// DepositOp deposits Amount into Account. Account must already be active;
+// there is no constructor that accepts a pending or closed account.
+type DepositOp struct {
+	Account *bank.ActiveAccount
+	Amount  float64
+}
+
+func (op DepositOp) sealed() {}
+
+func (op DepositOp) apply() Result {
+	bank.Deposit(op.Account, op.Amount)
+	return Result{ID: op.Account.GetID(), Balance: op.Account.GetBalance(), State: "active"}
+}
+
+// WithdrawOp withdraws Amount from Account. Account must already be active;
+// there is no constructor that accepts a closed account, so a WithdrawOp
+// can never target a closed one.
+type WithdrawOp struct {
+	Account *bank.ActiveAccount
+	Amount  float64
+}
+
+func (op WithdrawOp) sealed() {}
+
+func (op WithdrawOp) apply() Result {
+	if !bank.Withdraw(op.Account, op.Amount) {
+		return Result{
+			ID:    op.Account.GetID(),
+			State: "active",
+			Err:   errInsufficientFunds(op.Account.GetID()),
+		}
+	}
+	return Result{ID: op.Account.GetID(), Balance: op.Account.GetBalance(), State: "active"}
+}
+
+// CloseOp closes Account, transitioning it to closed.
+type CloseOp struct {
+	Account *bank.ActiveAccount
+}
+
+func (op CloseOp) sealed() {}
+
+func (op CloseOp) apply() Result {
+	closedAcc := bank.Close(op.Account)
+	return Result{ID: closedAcc.GetID(), Balance: closedAcc.GetBalance(), State: "closed"}
+}
+
+// ActivatePendingOp reactivates a pending account.
+type ActivatePendingOp struct {
+	Account *bank.PendingAccount
+}
+
+func (op ActivatePendingOp) sealed() {}
+
+func (op ActivatePendingOp) apply() Result {
+	activeAcc := bank.ActivatePending(op.Account)
+	return Result{ID: activeAcc.GetID(), Balance: activeAcc.GetBalance(), State: "active"}
+}
+
+// ActivateClosedOp reactivates a closed account.
+type ActivateClosedOp struct {
+	Account *bank.ClosedAccount
+}
+
+func (op ActivateClosedOp) sealed() {}
+
+func (op ActivateClosedOp) apply() Result {
+	activeAcc := bank.ActivateClosed(op.Account)
+	return Result{ID: activeAcc.GetID(), Balance: activeAcc.GetBalance(), State: "active"}
+}
+
+// Compile-time checks that each Op variant targets the intended state.
+var (
+	_ Op[active]  = DepositOp{}
+	_ Op[active]  = WithdrawOp{}
+	_ Op[active]  = CloseOp{}
+	_ Op[pending] = ActivatePendingOp{}
+	_ Op[closed]  = ActivateClosedOp{}
+)
+
+type insufficientFundsError struct{ id string }
+
+func (e insufficientFundsError) Error() string {
+	return "pipeline: insufficient funds in account " + e.id
+}
+
+func errInsufficientFunds(id string) error {
+	return insufficientFundsError{id: id}
+}
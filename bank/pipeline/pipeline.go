@@ -0,0 +1,75 @@
+// Package pipeline processes streams of typed operations against many
+// bank.Account values concurrently: one Worker per lifecycle state, fanned
+// in through a generic channel aggregator.
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Aggregate fans in cs into a single channel, closing it once every input
+// channel has been drained.
+func Aggregate[T any](cs ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	var wg sync.WaitGroup
+	wg.Add(len(cs))
+	for _, c := range cs {
+		go func(c <-chan T) {
+			defer wg.Done()
+			for v := range c {
+				out <- v
+			}
+		}(c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// Results is the fanned-in stream of Result values produced by Run.
+type Results = <-chan Result
+
+// Run starts one Worker per account state, fans their output together with
+// Aggregate, and cancels ctx on the first Result carrying a non-nil Err.
+// The returned CancelFunc lets the caller shut the pipeline down early.
+func Run(
+	ctx context.Context,
+	activeOps <-chan Op[active],
+	pendingOps <-chan Op[pending],
+	closedOps <-chan Op[closed],
+) (Results, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	activeWorker, activeOut := NewWorker(activeOps)
+	pendingWorker, pendingOut := NewWorker(pendingOps)
+	closedWorker, closedOut := NewWorker(closedOps)
+
+	go activeWorker.Run(ctx)
+	go pendingWorker.Run(ctx)
+	go closedWorker.Run(ctx)
+
+	merged := Aggregate(activeOut, pendingOut, closedOut)
+
+	results := make(chan Result)
+	go func() {
+		defer close(results)
+		for res := range merged {
+			// Always deliver res before acting on it: cancel() closes
+			// ctx.Done() synchronously, and a select between that and
+			// "results <- res" here would race against the cancellation
+			// we ourselves just triggered, occasionally dropping res.
+			results <- res
+			if res.Err != nil {
+				cancel()
+			}
+		}
+	}()
+
+	return results, cancel
+}
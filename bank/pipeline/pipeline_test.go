@@ -0,0 +1,173 @@
+package pipeline
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/abiiranathan/golang-type-state-pattern/bank"
+)
+
+func runActive(t *testing.T, ops ...Op[active]) []Result {
+	t.Helper()
+
+	activeOps := make(chan Op[active], len(ops))
+	for _, op := range ops {
+		activeOps <- op
+	}
+	close(activeOps)
+
+	pendingOps := make(chan Op[pending])
+	closedOps := make(chan Op[closed])
+	close(pendingOps)
+	close(closedOps)
+
+	results, cancel := Run(context.Background(), activeOps, pendingOps, closedOps)
+	defer cancel()
+
+	var got []Result
+	for r := range results {
+		got = append(got, r)
+	}
+	return got
+}
+
+func TestRunAppliesDepositsAndWithdrawals(t *testing.T) {
+	acc := bank.NewStandard("ACC-1", 100)
+
+	got := runActive(t,
+		DepositOp{Account: acc, Amount: 50},
+		WithdrawOp{Account: acc, Amount: 30},
+	)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d results, want 2", len(got))
+	}
+	for _, r := range got {
+		if r.Err != nil {
+			t.Fatalf("unexpected error: %v", r.Err)
+		}
+	}
+	if acc.GetBalance() != 120 {
+		t.Fatalf("balance = %v, want 120", acc.GetBalance())
+	}
+}
+
+func TestRunSurfacesInsufficientFunds(t *testing.T) {
+	acc := bank.NewStandard("ACC-2", 10)
+
+	got := runActive(t, WithdrawOp{Account: acc, Amount: 1000})
+
+	if len(got) != 1 || got[0].Err == nil {
+		t.Fatalf("got %+v, want a single result with a non-nil Err", got)
+	}
+}
+
+func TestAggregateDrainsAllInputs(t *testing.T) {
+	a := make(chan int, 2)
+	b := make(chan int, 2)
+	a <- 1
+	a <- 2
+	b <- 3
+	b <- 4
+	close(a)
+	close(b)
+
+	sum := 0
+	for v := range Aggregate[int](a, b) {
+		sum += v
+	}
+	if sum != 10 {
+		t.Fatalf("sum = %d, want 10", sum)
+	}
+}
+
+// TestPropertyRandomWalkPreservesBalanceInvariant random-walks a sequence of
+// deposits and withdrawals against a single active account through the
+// pipeline and checks that the account's final balance, and every
+// intermediate Result.Balance, matches what applying the same sequence
+// directly would produce - in particular, it never goes negative.
+//
+// Withdrawal amounts are capped at the running balance so every op
+// succeeds: Run cancels the whole pipeline on the first Result.Err, and
+// once that fires there's no guarantee further already-buffered ops still
+// get applied, which would make "want" impossible to predict here.
+func TestPropertyRandomWalkPreservesBalanceInvariant(t *testing.T) {
+	walk := func(seed int64, rawSteps uint8) bool {
+		rng := rand.New(rand.NewSource(seed))
+		steps := int(rawSteps%20) + 1
+
+		acc := bank.NewStandard("ACC-PROP", 100)
+		want := acc.GetBalance()
+
+		ops := make([]Op[active], steps)
+		wants := make([]float64, steps)
+		for i := 0; i < steps; i++ {
+			amount := float64(rng.Intn(50))
+			if rng.Intn(2) == 0 {
+				ops[i] = DepositOp{Account: acc, Amount: amount}
+				want += amount
+				wants[i] = want
+				continue
+			}
+
+			amount = math.Min(amount, want)
+			ops[i] = WithdrawOp{Account: acc, Amount: amount}
+			want -= amount
+			wants[i] = want
+		}
+
+		// A single active Worker applies ops in order, so results arrive in
+		// the same order as ops.
+		got := runActive(t, ops...)
+		if len(got) != steps {
+			return false
+		}
+		for i, r := range got {
+			if r.Err != nil || r.Balance < 0 || r.Balance != wants[i] {
+				return false
+			}
+		}
+
+		return acc.GetBalance() == want
+	}
+
+	if err := quick.Check(walk, &quick.Config{MaxCount: 200}); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkPipelineThroughput(b *testing.B) {
+	acc := bank.NewStandard("BENCH-PIPELINE", 1_000_000)
+
+	activeOps := make(chan Op[active], b.N)
+	pendingOps := make(chan Op[pending])
+	closedOps := make(chan Op[closed])
+	close(pendingOps)
+	close(closedOps)
+
+	for i := 0; i < b.N; i++ {
+		activeOps <- DepositOp{Account: acc, Amount: 1}
+	}
+	close(activeOps)
+
+	b.ResetTimer()
+	results, cancel := Run(context.Background(), activeOps, pendingOps, closedOps)
+	defer cancel()
+	for range results {
+	}
+}
+
+// BenchmarkSingleGoroutineBaseline applies the same number of deposits
+// directly via bank.Deposit on the calling goroutine, for comparison
+// against BenchmarkPipelineThroughput.
+func BenchmarkSingleGoroutineBaseline(b *testing.B) {
+	acc := bank.NewStandard("BENCH-BASELINE", 1_000_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bank.Deposit(acc, 1)
+	}
+}
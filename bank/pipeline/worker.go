@@ -0,0 +1,40 @@
+package pipeline
+
+import "context"
+
+// Worker applies every Op[S] it reads from in, writing one Result per Op to
+// its own output channel until In closes or ctx is cancelled.
+type Worker[S state] struct {
+	in  <-chan Op[S]
+	out chan Result
+}
+
+// NewWorker returns a Worker reading from in, and the channel its results
+// are published on.
+func NewWorker[S state](in <-chan Op[S]) (*Worker[S], <-chan Result) {
+	out := make(chan Result)
+	return &Worker[S]{in: in, out: out}, out
+}
+
+// Run applies ops until in closes or ctx is cancelled, then closes the
+// worker's output channel.
+func (w *Worker[S]) Run(ctx context.Context) {
+	defer close(w.out)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case op, ok := <-w.in:
+			if !ok {
+				return
+			}
+
+			select {
+			case w.out <- op.apply():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
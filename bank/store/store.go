@@ -0,0 +1,150 @@
+// Package store persists bank.Account values and rehydrates them back into
+// the correctly-typed *bank.Account[T], since an Account's state lives only
+// in its type parameter and can't otherwise survive a round trip through
+// JSON.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abiiranathan/golang-type-state-pattern/bank"
+)
+
+// record is the wire format: {id, balance, state}.
+type record struct {
+	ID      string  `json:"id"`
+	Balance float64 `json:"balance"`
+	State   string  `json:"state"`
+}
+
+// Save encodes an account of any bank.Account type-state into its persisted
+// form. acc must be one of *bank.ActiveAccount, *bank.PendingAccount or
+// *bank.ClosedAccount.
+func Save(acc any) ([]byte, error) {
+	switch a := acc.(type) {
+	case *bank.ActiveAccount:
+		return json.Marshal(record{ID: a.GetID(), Balance: a.GetBalance(), State: "active"})
+	case *bank.PendingAccount:
+		return json.Marshal(record{ID: a.GetID(), Balance: a.GetBalance(), State: "pending"})
+	case *bank.ClosedAccount:
+		return json.Marshal(record{ID: a.GetID(), Balance: a.GetBalance(), State: "closed"})
+	default:
+		return nil, fmt.Errorf("store: unsupported account type %T", acc)
+	}
+}
+
+// LoadActive decodes data, which must have been Saved from an active account.
+func LoadActive(data []byte) (*bank.ActiveAccount, error) {
+	rec, err := decode(data, "active")
+	if err != nil {
+		return nil, err
+	}
+	return &bank.ActiveAccount{ID: rec.ID, Balance: rec.Balance}, nil
+}
+
+// LoadPending decodes data, which must have been Saved from a pending account.
+func LoadPending(data []byte) (*bank.PendingAccount, error) {
+	rec, err := decode(data, "pending")
+	if err != nil {
+		return nil, err
+	}
+	return &bank.PendingAccount{ID: rec.ID, Balance: rec.Balance}, nil
+}
+
+// LoadClosed decodes data, which must have been Saved from a closed account.
+func LoadClosed(data []byte) (*bank.ClosedAccount, error) {
+	rec, err := decode(data, "closed")
+	if err != nil {
+		return nil, err
+	}
+	return &bank.ClosedAccount{ID: rec.ID, Balance: rec.Balance}, nil
+}
+
+func decode(data []byte, want string) (record, error) {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return record{}, err
+	}
+	if rec.State != want {
+		return record{}, fmt.Errorf("store: expected %q account, got %q", want, rec.State)
+	}
+	return rec, nil
+}
+
+// Visitor receives a persisted account through the method matching its
+// state, so a caller driven by Visit never needs an any-typed account.
+type Visitor interface {
+	VisitActive(*bank.ActiveAccount) error
+	VisitPending(*bank.PendingAccount) error
+	VisitClosed(*bank.ClosedAccount) error
+}
+
+// dispatch is a registry, keyed by state string, of closures that decode a
+// record and call the matching Visitor method. Populated once in init, one
+// entry per bank.Account state.
+var dispatch = map[string]func(record, Visitor) error{}
+
+func init() {
+	dispatch["active"] = func(rec record, v Visitor) error {
+		return v.VisitActive(&bank.ActiveAccount{ID: rec.ID, Balance: rec.Balance})
+	}
+	dispatch["pending"] = func(rec record, v Visitor) error {
+		return v.VisitPending(&bank.PendingAccount{ID: rec.ID, Balance: rec.Balance})
+	}
+	dispatch["closed"] = func(rec record, v Visitor) error {
+		return v.VisitClosed(&bank.ClosedAccount{ID: rec.ID, Balance: rec.Balance})
+	}
+}
+
+// Visit decodes data and dispatches it to the matching Visitor method.
+func Visit(data []byte, v Visitor) error {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+
+	fn, ok := dispatch[rec.State]
+	if !ok {
+		return fmt.Errorf("store: unknown account state %q", rec.State)
+	}
+	return fn(rec, v)
+}
+
+// Bucket groups a heterogeneous set of persisted accounts so callers can
+// drive the state machine after loading without ever holding an any-typed
+// account.
+type Bucket struct {
+	records [][]byte
+}
+
+// NewBucket returns an empty Bucket.
+func NewBucket() *Bucket {
+	return &Bucket{}
+}
+
+// Add saves acc and appends it to the bucket.
+func (b *Bucket) Add(acc any) error {
+	data, err := Save(acc)
+	if err != nil {
+		return err
+	}
+	b.records = append(b.records, data)
+	return nil
+}
+
+// Each calls Visit for every account in the bucket, in insertion order,
+// stopping at the first error.
+func (b *Bucket) Each(v Visitor) error {
+	for _, data := range b.records {
+		if err := Visit(data, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of accounts in the bucket.
+func (b *Bucket) Len() int {
+	return len(b.records)
+}
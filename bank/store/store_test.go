@@ -0,0 +1,139 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abiiranathan/golang-type-state-pattern/bank"
+)
+
+func TestSaveLoadActiveRoundTrip(t *testing.T) {
+	acc := bank.NewStandard("ACC-1", 42)
+
+	data, err := Save(acc)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := LoadActive(data)
+	if err != nil {
+		t.Fatalf("LoadActive: %v", err)
+	}
+	if got.GetID() != acc.GetID() || got.GetBalance() != acc.GetBalance() {
+		t.Fatalf("LoadActive = %+v, want ID=%q Balance=%v", got, acc.GetID(), acc.GetBalance())
+	}
+}
+
+func TestSaveLoadPendingAndClosedRoundTrip(t *testing.T) {
+	pending := &bank.PendingAccount{ID: "ACC-2", Balance: 10}
+	data, err := Save(pending)
+	if err != nil {
+		t.Fatalf("Save pending: %v", err)
+	}
+	if _, err := LoadPending(data); err != nil {
+		t.Fatalf("LoadPending: %v", err)
+	}
+
+	closed := &bank.ClosedAccount{ID: "ACC-3", Balance: 0}
+	data, err = Save(closed)
+	if err != nil {
+		t.Fatalf("Save closed: %v", err)
+	}
+	if _, err := LoadClosed(data); err != nil {
+		t.Fatalf("LoadClosed: %v", err)
+	}
+}
+
+func TestLoadRejectsWrongState(t *testing.T) {
+	acc := bank.NewStandard("ACC-4", 100)
+	data, err := Save(acc)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if _, err := LoadPending(data); err == nil {
+		t.Fatal("LoadPending accepted data saved from an active account")
+	}
+	if _, err := LoadClosed(data); err == nil {
+		t.Fatal("LoadClosed accepted data saved from an active account")
+	}
+}
+
+type recordingVisitor struct {
+	order   []string
+	failOn  string
+	failErr error
+}
+
+func (v *recordingVisitor) VisitActive(a *bank.ActiveAccount) error {
+	return v.visit("active:" + a.GetID())
+}
+
+func (v *recordingVisitor) VisitPending(a *bank.PendingAccount) error {
+	return v.visit("pending:" + a.GetID())
+}
+
+func (v *recordingVisitor) VisitClosed(a *bank.ClosedAccount) error {
+	return v.visit("closed:" + a.GetID())
+}
+
+func (v *recordingVisitor) visit(tag string) error {
+	if v.failOn != "" && tag == v.failOn {
+		return v.failErr
+	}
+	v.order = append(v.order, tag)
+	return nil
+}
+
+func TestBucketEachVisitsInInsertionOrder(t *testing.T) {
+	b := NewBucket()
+	if err := b.Add(bank.NewStandard("ACC-1", 1)); err != nil {
+		t.Fatalf("Add active: %v", err)
+	}
+	if err := b.Add(&bank.PendingAccount{ID: "ACC-2", Balance: 2}); err != nil {
+		t.Fatalf("Add pending: %v", err)
+	}
+	if err := b.Add(&bank.ClosedAccount{ID: "ACC-3", Balance: 3}); err != nil {
+		t.Fatalf("Add closed: %v", err)
+	}
+	if b.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", b.Len())
+	}
+
+	v := &recordingVisitor{}
+	if err := b.Each(v); err != nil {
+		t.Fatalf("Each: %v", err)
+	}
+
+	want := []string{"active:ACC-1", "pending:ACC-2", "closed:ACC-3"}
+	if len(v.order) != len(want) {
+		t.Fatalf("order = %v, want %v", v.order, want)
+	}
+	for i := range want {
+		if v.order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", v.order, want)
+		}
+	}
+}
+
+func TestBucketEachStopsAtFirstError(t *testing.T) {
+	b := NewBucket()
+	if err := b.Add(bank.NewStandard("ACC-1", 1)); err != nil {
+		t.Fatalf("Add active: %v", err)
+	}
+	if err := b.Add(&bank.PendingAccount{ID: "ACC-2", Balance: 2}); err != nil {
+		t.Fatalf("Add pending: %v", err)
+	}
+	if err := b.Add(&bank.ClosedAccount{ID: "ACC-3", Balance: 3}); err != nil {
+		t.Fatalf("Add closed: %v", err)
+	}
+
+	wantErr := errors.New("boom")
+	v := &recordingVisitor{failOn: "pending:ACC-2", failErr: wantErr}
+	if err := b.Each(v); !errors.Is(err, wantErr) {
+		t.Fatalf("Each err = %v, want %v", err, wantErr)
+	}
+	if len(v.order) != 1 || v.order[0] != "active:ACC-1" {
+		t.Fatalf("order = %v, want Each to stop after the first entry", v.order)
+	}
+}
@@ -0,0 +1,117 @@
+// Command enumgen generates an enums.Set-backed strict enum from a
+// //go:generate directive, the same way stringer derives a String method
+// from a type declaration: give it the phantom tag type, the allowed enum
+// names and the exported Go identifiers you want, and it emits the New
+// call, the private instances and their public constructors.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+)
+
+func main() {
+	typeName := flag.String("type", "", "phantom tag type name, e.g. queueType")
+	alias := flag.String("name", "", "exported alias for Value[type], e.g. QueueType")
+	values := flag.String("values", "", "comma-separated enum names, e.g. FIFO,LIFO")
+	idents := flag.String("idents", "", "comma-separated exported Go identifiers, one per value")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name; defaults to $GOPACKAGE")
+	out := flag.String("out", "", "output file; defaults to <type>_gen.go")
+	flag.Parse()
+
+	if *typeName == "" || *alias == "" || *values == "" || *idents == "" {
+		fmt.Fprintln(os.Stderr, "enumgen: -type, -name, -values and -idents are all required")
+		os.Exit(2)
+	}
+	if *pkg == "" {
+		fmt.Fprintln(os.Stderr, "enumgen: -package is required outside of go generate")
+		os.Exit(2)
+	}
+
+	vals := strings.Split(*values, ",")
+	names := strings.Split(*idents, ",")
+	if len(vals) != len(names) {
+		fmt.Fprintln(os.Stderr, "enumgen: -values and -idents must have the same number of entries")
+		os.Exit(2)
+	}
+
+	path := *out
+	if path == "" {
+		path = strings.ToLower(*typeName) + "_gen.go"
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err := generate(f, *typeName, *alias, *pkg, vals, names); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate renders the enum source into buf and writes the gofmt-formatted
+// result to f, so a re-run of go generate reproduces the checked-in file
+// byte-for-byte instead of fighting gofmt on every diff.
+func generate(f *os.File, typeName, alias, pkg string, vals, idents []string) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by enumgen -type=%s; DO NOT EDIT.\n\n", typeName)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "type %s struct{}\n\n", typeName)
+	fmt.Fprintf(&buf, "// %s is a strict enum value backed by Set[%s].\n", alias, typeName)
+	fmt.Fprintf(&buf, "type %s = Value[%s]\n\n", alias, typeName)
+
+	setVar := lowerFirst(alias) + "Set"
+	valuesVar := lowerFirst(alias) + "Values"
+
+	fmt.Fprintf(&buf, "var %s, %s = New[%s](%q", setVar, valuesVar, typeName, alias)
+	for _, v := range vals {
+		fmt.Fprintf(&buf, ", %q", v)
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+
+	fmt.Fprintln(&buf, "var (")
+	for i, ident := range idents {
+		fmt.Fprintf(&buf, "\t%s = %s[%d]\n", varName(ident), valuesVar, i)
+	}
+	fmt.Fprintln(&buf, ")")
+	fmt.Fprintln(&buf)
+
+	for i, ident := range idents {
+		fmt.Fprintf(&buf, "// %s returns the %s %s value.\n", ident, vals[i], alias)
+		fmt.Fprintf(&buf, "func %s() %s { return %s }\n\n", ident, alias, varName(ident))
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(formatted)
+	return err
+}
+
+// varName derives the unexported instance name for an exported constructor
+// identifier: acronym identifiers (all-uppercase, e.g. FIFO) are lowercased
+// wholesale, everything else just has its leading rune lowercased.
+func varName(ident string) string {
+	if ident == strings.ToUpper(ident) {
+		return strings.ToLower(ident)
+	}
+	return lowerFirst(ident)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
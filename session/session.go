@@ -0,0 +1,249 @@
+// Package session applies the bank.Account[State] type-state discipline to a
+// multi-stage authenticated channel, modeled on smartcard pairing protocols:
+// Unpaired -> Paired -> Opened -> Authenticated -> Closed.
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// sessionSaltSize is the amount of fresh randomness mixed into every Pair,
+// so that two channels paired with the same pairingKey/ID/pin never derive
+// the same encKey/macKey and reuse an AES-CTR keystream.
+const sessionSaltSize = 16
+
+// Define phantom type markers
+type unpaired struct{}
+type paired struct{}
+type opened struct{}
+type authenticated struct{}
+type closed struct{}
+
+type state interface {
+	unpaired | paired | opened | authenticated | closed
+}
+
+// Channel is a secure messaging channel whose lifecycle is tracked in its
+// type parameter. Only Channel[authenticated] may Send/Receive.
+type Channel[S state] struct {
+	ID string
+
+	sessionSalt   []byte // random per-session entropy generated during Pair
+	pairingSecret []byte // set once Paired, consumed by Open
+	sessionKey    []byte // set once Opened, consumed by Mutual
+	encKey        []byte // AES-CTR key, valid only once Authenticated
+	macKey        []byte // HMAC-SHA256 key, valid only once Authenticated
+
+	// sendNonce and recvNonce are independent per-direction counters, like a
+	// TLS record layer's read/write sequence numbers: this party's own Send
+	// calls only ever advance sendNonce, and only frames carrying the exact
+	// next recvNonce are accepted by Receive.
+	sendNonce uint64
+	recvNonce uint64
+}
+
+// New creates a fresh, unpaired channel identified by id.
+func New(id string) *Channel[unpaired] {
+	return &Channel[unpaired]{ID: id}
+}
+
+// ==================================================
+// private method forces type-specific implementation
+// ==================================================
+
+// CanPair enforces that only an unpaired channel can pair.
+type CanPair[T unpaired] interface {
+	*Channel[T]
+	pair(pairingKey []byte) *Channel[paired]
+}
+
+// CanOpen enforces that only a paired channel can open.
+type CanOpen[T paired] interface {
+	*Channel[T]
+	open(pairingKey []byte) (*Channel[opened], error)
+}
+
+// CanAuthenticate enforces that only an opened channel can authenticate.
+type CanAuthenticate[T opened] interface {
+	*Channel[T]
+	authenticate(pin string) (*Channel[authenticated], error)
+}
+
+// CanSend enforces that only an authenticated channel can send or receive.
+type CanSend[T authenticated] interface {
+	*Channel[T]
+	send(plaintext []byte) ([]byte, error)
+	receive(frame []byte) ([]byte, error)
+}
+
+// CanClose allows closing a channel from any state.
+type CanClose[T state] interface {
+	*Channel[T]
+	close() *Channel[closed]
+}
+
+// pair generates a fresh session salt and derives a pairing secret bound to
+// the pairing key, this channel's ID and that salt. The salt is what keeps
+// repeated pairings of the same ID/pairingKey from ever deriving the same
+// downstream keys.
+func (c *Channel[unpaired]) pair(pairingKey []byte) *Channel[paired] {
+	salt := make([]byte, sessionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		panic("session: failed to generate session salt: " + err.Error())
+	}
+
+	return &Channel[paired]{
+		ID:            c.ID,
+		sessionSalt:   salt,
+		pairingSecret: hmacSum(pairingKey, append([]byte(c.ID), salt...)),
+	}
+}
+
+// open verifies the pairing key and derives the session key.
+func (c *Channel[paired]) open(pairingKey []byte) (*Channel[opened], error) {
+	expected := hmacSum(pairingKey, append([]byte(c.ID), c.sessionSalt...))
+	if !hmac.Equal(expected, c.pairingSecret) {
+		return nil, errors.New("session: pairing key mismatch")
+	}
+
+	return &Channel[opened]{
+		ID:          c.ID,
+		sessionSalt: c.sessionSalt,
+		sessionKey:  hmacSum(c.pairingSecret, append([]byte("session-key"), c.sessionSalt...)),
+	}, nil
+}
+
+// authenticate performs mutual authentication and derives the encryption and
+// MAC keys used by Send/Receive. The session salt generated in pair is
+// mixed in here too, so encKey/macKey are unique per paired session even
+// when the same pairingKey and pin are reused.
+func (c *Channel[opened]) authenticate(pin string) (*Channel[authenticated], error) {
+	if pin == "" {
+		return nil, errors.New("session: pin required")
+	}
+
+	material := hmacSum(c.sessionKey, append([]byte(pin), c.sessionSalt...))
+	extra := hmacSum(c.sessionKey, append([]byte("extend"), material...))
+	material = append(material, extra...)
+
+	return &Channel[authenticated]{
+		ID:     c.ID,
+		encKey: material[:16],
+		macKey: material[16:48],
+	}, nil
+}
+
+// send encrypts plaintext with AES-CTR and appends an HMAC-SHA256 tag over the
+// nonce, channel ID and ciphertext. sendNonce is incremented on every call.
+func (c *Channel[authenticated]) send(plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(c.encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[aes.BlockSize-8:], c.sendNonce)
+
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, plaintext)
+
+	frame := make([]byte, 8+len(ciphertext))
+	binary.BigEndian.PutUint64(frame[:8], c.sendNonce)
+	copy(frame[8:], ciphertext)
+
+	tag := hmacSum(c.macKey, append([]byte(c.ID), frame...))
+	c.sendNonce++
+	return append(frame, tag...), nil
+}
+
+// receive verifies the HMAC tag, rejects frames whose nonce doesn't match the
+// expected recvNonce (replay protection), decrypts the frame and advances
+// recvNonce.
+func (c *Channel[authenticated]) receive(frame []byte) ([]byte, error) {
+	const tagSize = sha256.Size
+	if len(frame) < 8+tagSize {
+		return nil, errors.New("session: frame too short")
+	}
+
+	body, tag := frame[:len(frame)-tagSize], frame[len(frame)-tagSize:]
+	expected := hmacSum(c.macKey, append([]byte(c.ID), body...))
+	if !hmac.Equal(expected, tag) {
+		return nil, errors.New("session: invalid MAC")
+	}
+
+	nonce := binary.BigEndian.Uint64(body[:8])
+	if nonce != c.recvNonce {
+		return nil, errors.New("session: unexpected nonce, possible replay")
+	}
+
+	block, err := aes.NewCipher(c.encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[aes.BlockSize-8:], nonce)
+
+	plaintext := make([]byte, len(body)-8)
+	cipher.NewCTR(block, iv).XORKeyStream(plaintext, body[8:])
+
+	c.recvNonce++
+	return plaintext, nil
+}
+
+// close tears down the channel; no further transitions are possible.
+func (c *Channel[S]) close() *Channel[closed] {
+	return &Channel[closed]{ID: c.ID}
+}
+
+// Public API functions with type constraints.
+// Pair, Open, Mutual, Send, Receive, and Close can only be called on channels
+// in the correct state.
+
+// Pair transitions an unpaired channel to paired using a shared pairing key.
+func Pair[T unpaired, C CanPair[T]](ch C, pairingKey []byte) *Channel[paired] {
+	return ch.pair(pairingKey)
+}
+
+// Open transitions a paired channel to opened, verifying the pairing key.
+func Open[T paired, C CanOpen[T]](ch C, pairingKey []byte) (*Channel[opened], error) {
+	return ch.open(pairingKey)
+}
+
+// Mutual performs mutual authentication, transitioning an opened channel to authenticated.
+func Mutual[T opened, C CanAuthenticate[T]](ch C, pin string) (*Channel[authenticated], error) {
+	return ch.authenticate(pin)
+}
+
+// Send encrypts and authenticates plaintext. Only an authenticated channel can send.
+func Send[T authenticated, C CanSend[T]](ch C, plaintext []byte) ([]byte, error) {
+	return ch.send(plaintext)
+}
+
+// Receive authenticates and decrypts a frame. Only an authenticated channel can receive.
+func Receive[T authenticated, C CanSend[T]](ch C, frame []byte) ([]byte, error) {
+	return ch.receive(frame)
+}
+
+// Close transitions a channel in any state to closed.
+func Close[T state, C CanClose[T]](ch C) *Channel[closed] {
+	return ch.close()
+}
+
+// These operations would cause compile-time errors:
+//
+//	Send(Pair(New("id"), key), []byte("hi"))    // Error: *Channel[paired] doesn't satisfy CanSend
+//	Mutual(New("id"), "1234")                   // Error: *Channel[unpaired] doesn't satisfy CanAuthenticate
+//	Open(New("id"), key)                        // Error: *Channel[unpaired] doesn't satisfy CanOpen
+
+func hmacSum(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
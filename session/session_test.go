@@ -0,0 +1,116 @@
+package session
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func pairOpenAuthenticate(t *testing.T, id string, pairingKey []byte, pin string) *Channel[authenticated] {
+	t.Helper()
+
+	opened, err := Open(Pair(New(id), pairingKey), pairingKey)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	ch, err := Mutual(opened, pin)
+	if err != nil {
+		t.Fatalf("Mutual: %v", err)
+	}
+	return ch
+}
+
+func TestSendReceiveRoundTrip(t *testing.T) {
+	ch := pairOpenAuthenticate(t, "ch-1", []byte("shared-pairing-key"), "1234")
+
+	plaintext := []byte("hello, authenticated channel")
+	frame, err := Send(ch, plaintext)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got, err := Receive(ch, frame)
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Fatalf("Receive = %q, want %q", got, plaintext)
+	}
+}
+
+func TestOpenRejectsWrongPairingKey(t *testing.T) {
+	paired := Pair(New("ch-2"), []byte("correct-key"))
+	if _, err := Open(paired, []byte("wrong-key")); err == nil {
+		t.Fatal("Open accepted a mismatched pairing key")
+	}
+}
+
+func TestReceiveRejectsReplayedFrame(t *testing.T) {
+	ch := pairOpenAuthenticate(t, "ch-3", []byte("shared-pairing-key"), "1234")
+
+	frame, err := Send(ch, []byte("first message"))
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, err := Receive(ch, frame); err != nil {
+		t.Fatalf("first Receive: %v", err)
+	}
+	if _, err := Receive(ch, frame); err == nil {
+		t.Fatal("Receive accepted a replayed frame")
+	}
+}
+
+func TestReceiveRejectsTamperedFrame(t *testing.T) {
+	ch := pairOpenAuthenticate(t, "ch-4", []byte("shared-pairing-key"), "1234")
+
+	frame, err := Send(ch, []byte("untampered"))
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	frame[len(frame)-1] ^= 0xFF
+
+	if _, err := Receive(ch, frame); err == nil {
+		t.Fatal("Receive accepted a tampered frame")
+	}
+}
+
+// TestIndependentSessionsDoNotShareKeystream guards against the
+// two-time-pad break: two channels paired and authenticated with the exact
+// same ID/pairingKey/pin must still derive distinct encKey/macKey, since
+// each Pair mixes in fresh randomness. If they didn't, encrypting the same
+// plaintext on both would produce identical ciphertext.
+func TestIndependentSessionsDoNotShareKeystream(t *testing.T) {
+	pairingKey := []byte("shared-pairing-key")
+	const pin = "1234"
+	plaintext := []byte("same plaintext, two independent sessions")
+
+	chA := pairOpenAuthenticate(t, "same-id", pairingKey, pin)
+	chB := pairOpenAuthenticate(t, "same-id", pairingKey, pin)
+
+	frameA, err := Send(chA, plaintext)
+	if err != nil {
+		t.Fatalf("Send (A): %v", err)
+	}
+	frameB, err := Send(chB, plaintext)
+	if err != nil {
+		t.Fatalf("Send (B): %v", err)
+	}
+
+	if string(frameA) == string(frameB) {
+		t.Fatal("two independently paired/authenticated sessions produced identical ciphertext for identical plaintext: keystream is being reused across sessions")
+	}
+}
+
+// TestInvalidStateTransitionsDoNotCompile builds testdata/compilefail, which
+// calls Send on a *Channel[paired], and fails if it compiles: Send must only
+// be callable on a *Channel[authenticated].
+func TestInvalidStateTransitionsDoNotCompile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	out, err := exec.Command("go", "build", "./testdata/compilefail").CombinedOutput()
+	if err == nil {
+		t.Fatalf("testdata/compilefail built successfully; it should have failed with a CanSend constraint error:\n%s", out)
+	}
+}
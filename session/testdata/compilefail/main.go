@@ -0,0 +1,11 @@
+// This program must NOT compile: it calls Send on a *session.Channel[paired],
+// which doesn't satisfy session.CanSend. See ../../session_test.go, which
+// builds this package and fails the test if it compiles successfully.
+package main
+
+import "github.com/abiiranathan/golang-type-state-pattern/session"
+
+func main() {
+	ch := session.Pair(session.New("id"), []byte("pairing-key"))
+	session.Send(ch, []byte("hi"))
+}
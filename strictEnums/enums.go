@@ -1,83 +1,199 @@
-// Package enums demonstrates a strict enum pattern in Go using generics and unexported fields.
-// phantom types are used to prevent external construction of enum values.
+// Package enums demonstrates a strict enum pattern in Go using generics and
+// unexported fields: phantom types prevent external packages from
+// constructing enum values, and New wires up parsing, (un)marshaling, and
+// database/sql and flag adapters for any enum built on top of it.
 package enums
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"reflect"
+	"sync"
 )
 
-type queueType[T any] struct {
-	_  [0]T
-	id uint8 // unexported - prevents external construction
+// Value is a strict enum value tagged by Tag. The unexported id and the
+// phantom [0]Tag field mean external packages cannot construct a Value
+// except by going through the Set returned from New.
+type Value[Tag any] struct {
+	_  [0]Tag
+	id uint8 // 0 means unset; valid ids start at 1
 }
 
-type QueueType = queueType[int]
+// Set owns the id<->name table for a family of Value[Tag] constants.
+type Set[Tag any] struct {
+	name   string
+	byID   map[uint8]string
+	byName map[string]uint8
+}
 
-// Private instances - cannot be modified externally
-var (
-	fifo       = QueueType{id: 1}
-	lifo       = QueueType{id: 2}
-	priority   = QueueType{id: 3}
-	roundRobin = QueueType{id: 4}
-)
+// registry maps a Tag's reflect.Type to its *Set[Tag], so Value[Tag]'s
+// methods can find their names without Value itself carrying a pointer.
+var registry sync.Map
 
-// Public constructors - only way to get valid instances
-func FIFO() QueueType       { return fifo }
-func LIFO() QueueType       { return lifo }
-func Priority() QueueType   { return priority }
-func RoundRobin() QueueType { return roundRobin }
-
-func (q queueType[int]) String() string {
-	switch q.id {
-	case 1:
-		return "FIFO"
-	case 2:
-		return "LIFO"
-	case 3:
-		return "PRIORITY"
-	case 4:
-		return "ROUND_ROBIN"
-	default:
-		panic("unreachable") // should never happen
+// New builds a Set and the Value[Tag] constant for each of values, in the
+// order given, and registers the Set so Value[Tag]'s methods (String,
+// MarshalJSON, Scan, ...) can find it later. id 0 is reserved as the
+// unset/invalid value.
+func New[Tag any](name string, values ...string) (Set[Tag], []Value[Tag]) {
+	s := &Set[Tag]{
+		name:   name,
+		byID:   make(map[uint8]string, len(values)),
+		byName: make(map[string]uint8, len(values)),
+	}
+
+	vals := make([]Value[Tag], len(values))
+	for i, v := range values {
+		id := uint8(i + 1)
+		s.byID[id] = v
+		s.byName[v] = id
+		vals[i] = Value[Tag]{id: id}
+	}
+
+	registry.Store(tagType[Tag](), s)
+	return *s, vals
+}
+
+func tagType[Tag any]() reflect.Type {
+	return reflect.TypeOf((*Tag)(nil)).Elem()
+}
+
+func setFor[Tag any]() (*Set[Tag], bool) {
+	v, ok := registry.Load(tagType[Tag]())
+	if !ok {
+		return nil, false
+	}
+	return v.(*Set[Tag]), true
+}
+
+// Parse looks up the Value[Tag] with the given name.
+func Parse[Tag any](name string) (Value[Tag], error) {
+	s, ok := setFor[Tag]()
+	if !ok {
+		return Value[Tag]{}, fmt.Errorf("enums: no Set registered for %s", tagType[Tag]())
+	}
+	return s.Parse(name)
+}
+
+// Parse looks up the Value with the given name within this Set.
+func (s Set[Tag]) Parse(name string) (Value[Tag], error) {
+	id, ok := s.byName[name]
+	if !ok {
+		return Value[Tag]{}, fmt.Errorf("enums: invalid %s value: %s", s.name, name)
+	}
+	return Value[Tag]{id: id}, nil
+}
+
+// Values returns every Value in this Set, in declaration order.
+func (s Set[Tag]) Values() []Value[Tag] {
+	vals := make([]Value[Tag], 0, len(s.byID))
+	for id := uint8(1); int(id) <= len(s.byID); id++ {
+		vals = append(vals, Value[Tag]{id: id})
+	}
+	return vals
+}
+
+// Flag returns a flag.Value that parses into dst, for use with flag.Var.
+func (s Set[Tag]) Flag(dst *Value[Tag]) flag.Value {
+	return &flagValue[Tag]{set: s, dst: dst}
+}
+
+type flagValue[Tag any] struct {
+	set Set[Tag]
+	dst *Value[Tag]
+}
+
+func (f *flagValue[Tag]) String() string {
+	if f.dst == nil {
+		return ""
+	}
+	return f.dst.String()
+}
+
+func (f *flagValue[Tag]) Set(s string) error {
+	v, err := f.set.Parse(s)
+	if err != nil {
+		return err
+	}
+	*f.dst = v
+	return nil
+}
+
+// String returns the enum name v was constructed with, or "UNKNOWN" if v is
+// the zero Value or its Set is no longer registered.
+func (v Value[Tag]) String() string {
+	s, ok := setFor[Tag]()
+	if !ok {
+		return "UNKNOWN"
 	}
+	name, ok := s.byID[v.id]
+	if !ok {
+		return "UNKNOWN"
+	}
+	return name
 }
 
-// JSON marshaling
-func (q queueType[T]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(q.String())
+// MarshalJSON encodes v as its enum name.
+func (v Value[Tag]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
 }
 
-func (q *queueType[T]) UnmarshalJSON(data []byte) error {
+// UnmarshalJSON decodes an enum name produced by MarshalJSON.
+func (v *Value[Tag]) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
 		return err
 	}
 
-	parsed, err := ParseQueueType(s)
+	parsed, err := Parse[Tag](s)
 	if err != nil {
 		return err
 	}
 
-	*q = any(parsed).(queueType[T])
+	*v = parsed
 	return nil
 }
 
-func ParseQueueType(s string) (QueueType, error) {
-	switch s {
-	case "FIFO":
-		return FIFO(), nil
-	case "LIFO":
-		return LIFO(), nil
-	case "PRIORITY":
-		return Priority(), nil
-	case "ROUND_ROBIN":
-		return RoundRobin(), nil
-	default:
-		return QueueType{}, fmt.Errorf("invalid queue type: %s", s)
+// MarshalText encodes v as its enum name, for encoding.TextMarshaler.
+func (v Value[Tag]) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText decodes an enum name, for encoding.TextUnmarshaler.
+func (v *Value[Tag]) UnmarshalText(text []byte) error {
+	parsed, err := Parse[Tag](string(text))
+	if err != nil {
+		return err
 	}
+
+	*v = parsed
+	return nil
+}
+
+// Value implements driver.Valuer so a Value[Tag] can be written with
+// database/sql as its enum name.
+func (v Value[Tag]) Value() (driver.Value, error) {
+	return v.String(), nil
 }
 
-func ProcessQueue(q QueueType) {
-	fmt.Printf("Processing %s queue\n", q)
+// Scan implements sql.Scanner so a Value[Tag] can be read back from a
+// database/sql column holding its enum name.
+func (v *Value[Tag]) Scan(src any) error {
+	switch x := src.(type) {
+	case string:
+		parsed, err := Parse[Tag](x)
+		if err != nil {
+			return err
+		}
+		*v = parsed
+		return nil
+	case []byte:
+		return v.Scan(string(x))
+	case nil:
+		*v = Value[Tag]{}
+		return nil
+	default:
+		return fmt.Errorf("enums: cannot scan %T into Value", src)
+	}
 }
@@ -0,0 +1,14 @@
+package enums
+
+import "fmt"
+
+//go:generate go run ../cmd/enumgen -type=queueType -name=QueueType -values=FIFO,LIFO,PRIORITY,ROUND_ROBIN -idents=FIFO,LIFO,Priority,RoundRobin -out=queue_type_gen.go
+
+// ParseQueueType parses a queue type name produced by QueueType.String.
+func ParseQueueType(s string) (QueueType, error) {
+	return Parse[queueType](s)
+}
+
+func ProcessQueue(q QueueType) {
+	fmt.Printf("Processing %s queue\n", q)
+}
@@ -0,0 +1,34 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+)
+
+// deriveChild derives index's child key material from w using a simplified
+// BIP-32-style HMAC-SHA512 scheme (chain-code mixing and curve-point
+// arithmetic are omitted; this is enough to demonstrate composing a new
+// type-state generic, HDDerive, on top of an Unlocked wallet), then
+// immediately reseals it as a new Locked wallet under childPassphrase.
+func (w *Wallet[unlocked]) deriveChild(index uint32, childPassphrase []byte) (*Wallet[locked], error) {
+	mac := hmac.New(sha512.New, w.cleartext)
+	var idx [4]byte
+	binary.BigEndian.PutUint32(idx[:], index)
+	mac.Write(idx[:])
+	sum := mac.Sum(nil)
+
+	childKey := sum[:32]
+	childID := fmt.Sprintf("%s/%d", w.ID, index)
+	childAddress := fmt.Sprintf("%s/%d", w.Address, index)
+
+	return New(childID, childAddress, childKey, childPassphrase)
+}
+
+// HDDerive derives the child wallet at index from an unlocked parent and
+// reseals it as a new Locked wallet under childPassphrase. Only an unlocked
+// wallet can derive children.
+func HDDerive[T unlocked, W CanDerive[T]](w W, index uint32, childPassphrase []byte) (*Wallet[locked], error) {
+	return w.deriveChild(index, childPassphrase)
+}
@@ -0,0 +1,202 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// keystoreV3 mirrors the Ethereum Web3 "v3" keystore JSON layout.
+//
+// This package computes the MAC as HMAC-SHA256 over the ciphertext instead
+// of the v3 Keccak-256 MAC, since the standard library doesn't vendor a
+// Keccak implementation. The envelope shape is otherwise unchanged, but the
+// MAC differs, so keystores written here do not open in v3 tooling and v3
+// keystores from elsewhere do not open here.
+type keystoreV3 struct {
+	Version int      `json:"version"`
+	ID      string   `json:"id"`
+	Address string   `json:"address"`
+	Crypto  cryptoV3 `json:"crypto"`
+}
+
+type cryptoV3 struct {
+	Cipher       string       `json:"cipher"`
+	CipherText   string       `json:"ciphertext"`
+	CipherParams cipherParams `json:"cipherparams"`
+	KDF          string       `json:"kdf"`
+	KDFParams    scryptParams `json:"kdfparams"`
+	MAC          string       `json:"mac"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+// Default scrypt cost parameters, matching geth's "light" keystore preset.
+const (
+	scryptN     = 1 << 12
+	scryptR     = 8
+	scryptP     = 1
+	scryptDKLen = 32
+)
+
+// sealKeystore encrypts key under passphrase and returns a v3 keystore.
+func sealKeystore(id, address string, key, passphrase []byte) (keystoreV3, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return keystoreV3{}, err
+	}
+
+	derived, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return keystoreV3{}, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return keystoreV3{}, err
+	}
+
+	block, err := aes.NewCipher(derived[:16])
+	if err != nil {
+		return keystoreV3{}, err
+	}
+
+	ciphertext := make([]byte, len(key))
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, key)
+
+	mac := hmacSum(derived[16:32], ciphertext)
+
+	return keystoreV3{
+		Version: 3,
+		ID:      id,
+		Address: address,
+		Crypto: cryptoV3{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(ciphertext),
+			CipherParams: cipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: scryptParams{
+				DKLen: scryptDKLen,
+				N:     scryptN,
+				R:     scryptR,
+				P:     scryptP,
+				Salt:  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+	}, nil
+}
+
+// openKeystore decrypts ks under passphrase, verifying the MAC before
+// returning cleartext key material.
+func openKeystore(ks keystoreV3, passphrase []byte) ([]byte, error) {
+	if ks.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("wallet: unsupported cipher %q", ks.Crypto.Cipher)
+	}
+	if ks.Crypto.KDF != "scrypt" {
+		return nil, fmt.Errorf("wallet: unsupported KDF %q", ks.Crypto.KDF)
+	}
+
+	salt, err := hex.DecodeString(ks.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	p := ks.Crypto.KDFParams
+	if err := validateScryptParams(p); err != nil {
+		return nil, err
+	}
+
+	derived, err := scrypt.Key(passphrase, salt, p.N, p.R, p.P, p.DKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	wantMAC, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(hmacSum(derived[16:32], ciphertext), wantMAC) {
+		return nil, errors.New("wallet: invalid passphrase or corrupted keystore")
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derived[:16])
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, iv).XORKeyStream(key, ciphertext)
+	return key, nil
+}
+
+// validateScryptParams rejects KDF parameters a corrupted or malicious
+// keystore could carry before they reach scrypt.Key. In particular derived
+// must be at least scryptDKLen bytes: openKeystore always slices
+// derived[:16] and derived[16:32], and scrypt.Key will happily return fewer
+// bytes than that (or, if asked for too many, allocate a large buffer) for a
+// small or huge DKLen respectively.
+func validateScryptParams(p scryptParams) error {
+	if p.DKLen < scryptDKLen {
+		return fmt.Errorf("wallet: scrypt dklen %d is too short, want at least %d", p.DKLen, scryptDKLen)
+	}
+	if p.DKLen > 1024 {
+		return fmt.Errorf("wallet: scrypt dklen %d is too long", p.DKLen)
+	}
+	if p.N <= 1 || p.N&(p.N-1) != 0 || p.N > 1<<20 {
+		return fmt.Errorf("wallet: scrypt N %d out of range", p.N)
+	}
+	if p.R <= 0 || p.R > 1024 {
+		return fmt.Errorf("wallet: scrypt r %d out of range", p.R)
+	}
+	if p.P <= 0 || p.P > 1024 {
+		return fmt.Errorf("wallet: scrypt p %d out of range", p.P)
+	}
+	return nil
+}
+
+func marshalKeystore(ks keystoreV3) ([]byte, error) {
+	return json.Marshal(ks)
+}
+
+func unmarshalKeystore(data []byte) (keystoreV3, error) {
+	var ks keystoreV3
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return keystoreV3{}, err
+	}
+	return ks, nil
+}
+
+func hmacSum(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,179 @@
+// Package wallet applies the bank.Account[State] type-state pattern to a
+// keystore-like object: a Wallet only ever holds cleartext key material
+// while Unlocked or Signing, and the compiler - not a runtime check - is
+// what stops a Locked wallet from signing anything.
+package wallet
+
+import "errors"
+
+// Define phantom type markers
+type locked struct{}
+type unlocked struct{}
+type signing struct{}
+
+type state interface {
+	locked | unlocked | signing
+}
+
+type unlockedOrSigning interface {
+	unlocked | signing
+}
+
+// Wallet wraps an encrypted Web3 v3 keystore. Only Unlocked and Signing
+// wallets additionally hold cleartext key material.
+type Wallet[S state] struct {
+	ID        string
+	Address   string
+	keystore  keystoreV3
+	cleartext []byte // nil unless Unlocked or Signing
+}
+
+// New encrypts key under passphrase and returns a Locked wallet. key is not
+// retained by the caller's slice; zero it yourself once New returns.
+func New(id, address string, key, passphrase []byte) (*Wallet[locked], error) {
+	ks, err := sealKeystore(id, address, key, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet[locked]{ID: id, Address: address, keystore: ks}, nil
+}
+
+// FromKeystoreJSON parses a Web3 v3 keystore JSON blob into a Locked wallet.
+func FromKeystoreJSON(data []byte) (*Wallet[locked], error) {
+	ks, err := unmarshalKeystore(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet[locked]{ID: ks.ID, Address: ks.Address, keystore: ks}, nil
+}
+
+// KeystoreJSON serializes the wallet's encrypted envelope, regardless of
+// its current state, so a caller can persist any wallet instance.
+func (w *Wallet[S]) KeystoreJSON() ([]byte, error) {
+	return marshalKeystore(w.keystore)
+}
+
+// ==================================================
+// private method forces type-specific implementation
+// ==================================================
+
+// CanUnlock enforces that only a locked wallet can unlock.
+type CanUnlock[T locked] interface {
+	*Wallet[T]
+	unlock(passphrase []byte) (*Wallet[unlocked], error)
+}
+
+// CanLock enforces that an unlocked or signing wallet can lock, which zeroes
+// its cleartext key material.
+type CanLock[T unlockedOrSigning] interface {
+	*Wallet[T]
+	lock() *Wallet[locked]
+}
+
+// CanBeginSign enforces that only an unlocked wallet can begin signing.
+type CanBeginSign[T unlocked] interface {
+	*Wallet[T]
+	beginSign() *Wallet[signing]
+}
+
+// CanSign enforces that only a signing wallet can sign.
+type CanSign[T signing] interface {
+	*Wallet[T]
+	sign(digest []byte) ([]byte, error)
+}
+
+// CanEndSign enforces that only a signing wallet can end signing.
+type CanEndSign[T signing] interface {
+	*Wallet[T]
+	endSign() *Wallet[unlocked]
+}
+
+// CanDerive enforces that only an unlocked wallet can derive a child.
+type CanDerive[T unlocked] interface {
+	*Wallet[T]
+	deriveChild(index uint32, childPassphrase []byte) (*Wallet[locked], error)
+}
+
+func (w *Wallet[locked]) unlock(passphrase []byte) (*Wallet[unlocked], error) {
+	key, err := openKeystore(w.keystore, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet[unlocked]{ID: w.ID, Address: w.Address, keystore: w.keystore, cleartext: key}, nil
+}
+
+func (w *Wallet[S]) lock() *Wallet[locked] {
+	zero(w.cleartext)
+	w.cleartext = nil
+	return &Wallet[locked]{ID: w.ID, Address: w.Address, keystore: w.keystore}
+}
+
+// beginSign clones the cleartext key material rather than sharing w's
+// backing array: w (still an Unlocked handle the caller may hold onto) can
+// independently Lock and zero its own copy without corrupting the Signing
+// wallet derived here.
+func (w *Wallet[unlocked]) beginSign() *Wallet[signing] {
+	return &Wallet[signing]{ID: w.ID, Address: w.Address, keystore: w.keystore, cleartext: cloneBytes(w.cleartext)}
+}
+
+// sign computes an HMAC-SHA256 over digest keyed by the wallet's cleartext
+// key material. A real signing scheme (ECDSA/Ed25519) would replace this,
+// but the type-state shape - cleartext reachable only while Signing - is
+// the point being demonstrated.
+func (w *Wallet[signing]) sign(digest []byte) ([]byte, error) {
+	if len(digest) == 0 {
+		return nil, errEmptyDigest
+	}
+	return hmacSum(w.cleartext, digest), nil
+}
+
+// endSign clones the cleartext key material for the same reason beginSign
+// does: the Signing handle w may still be Locked independently by its
+// holder, and must not be able to zero the returned Unlocked wallet's copy.
+func (w *Wallet[signing]) endSign() *Wallet[unlocked] {
+	return &Wallet[unlocked]{ID: w.ID, Address: w.Address, keystore: w.keystore, cleartext: cloneBytes(w.cleartext)}
+}
+
+// Public API functions with type constraints.
+// Unlock, Lock, BeginSign, Sign, EndSign, and HDDerive can only be called on
+// wallets in the correct state.
+
+// Unlock transitions a locked wallet to unlocked, decrypting its keystore.
+func Unlock[T locked, W CanUnlock[T]](w W, passphrase []byte) (*Wallet[unlocked], error) {
+	return w.unlock(passphrase)
+}
+
+// Lock transitions an unlocked or signing wallet to locked, zeroing its
+// cleartext key material.
+func Lock[T unlockedOrSigning, W CanLock[T]](w W) *Wallet[locked] {
+	return w.lock()
+}
+
+// BeginSign transitions an unlocked wallet to signing.
+func BeginSign[T unlocked, W CanBeginSign[T]](w W) *Wallet[signing] {
+	return w.beginSign()
+}
+
+// Sign signs digest. Only a signing wallet can sign.
+func Sign[T signing, W CanSign[T]](w W, digest []byte) ([]byte, error) {
+	return w.sign(digest)
+}
+
+// EndSign transitions a signing wallet back to unlocked.
+func EndSign[T signing, W CanEndSign[T]](w W) *Wallet[unlocked] {
+	return w.endSign()
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+func cloneBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	copy(out, b)
+	return out
+}
+
+var errEmptyDigest = errors.New("wallet: digest must not be empty")
@@ -0,0 +1,106 @@
+package wallet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func unlockedTestWallet(t *testing.T) (*Wallet[unlocked], []byte) {
+	t.Helper()
+
+	key := []byte("super-secret-key-material-32byt")
+	passphrase := []byte("correct horse battery staple")
+
+	locked, err := New("w-1", "0xABC", key, passphrase)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	unlocked, err := Unlock(locked, passphrase)
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	return unlocked, key
+}
+
+func TestUnlockLockBeginSignSignEndSignRoundTrip(t *testing.T) {
+	unlocked, _ := unlockedTestWallet(t)
+
+	signing := BeginSign(unlocked)
+	digest := []byte("message to sign")
+	sig, err := Sign(signing, digest)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if len(sig) == 0 {
+		t.Fatal("Sign returned an empty signature")
+	}
+
+	backToUnlocked := EndSign(signing)
+	sig2, err := Sign(BeginSign(backToUnlocked), digest)
+	if err != nil {
+		t.Fatalf("Sign after EndSign/BeginSign: %v", err)
+	}
+	if !bytes.Equal(sig, sig2) {
+		t.Fatal("signature changed across an EndSign/BeginSign round trip with no Lock in between")
+	}
+
+	Lock(backToUnlocked)
+}
+
+func TestUnlockRejectsWrongPassphrase(t *testing.T) {
+	key := []byte("super-secret-key-material-32byt")
+	locked, err := New("w-2", "0xDEF", key, []byte("correct passphrase"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := Unlock(locked, []byte("wrong passphrase")); err == nil {
+		t.Fatal("Unlock accepted a wrong passphrase")
+	}
+}
+
+// TestLockDoesNotCorruptSiblingHandle guards against the bug fixed in
+// beginSign/endSign: cloning cleartext on every transition means Lock on one
+// handle zeroes only that handle's own copy, never a sibling Signing or
+// Unlocked handle still derived from the same Wallet[unlocked].
+func TestLockDoesNotCorruptSiblingHandle(t *testing.T) {
+	unlocked, _ := unlockedTestWallet(t)
+
+	signing := BeginSign(unlocked)
+	msg := []byte("message to sign")
+
+	want, err := Sign(signing, msg)
+	if err != nil {
+		t.Fatalf("Sign before Lock: %v", err)
+	}
+
+	// unlocked is still a live handle the caller holds onto; Locking it must
+	// not zero the cleartext backing signing, which was derived from it.
+	Lock(unlocked)
+
+	sig, err := Sign(signing, msg)
+	if err != nil {
+		t.Fatalf("Sign on sibling Signing handle after Lock: %v", err)
+	}
+	if len(sig) == 0 {
+		t.Fatal("Sign returned an empty signature")
+	}
+	if !bytes.Equal(sig, want) {
+		t.Fatal("signature changed after Lock on the sibling Unlocked handle: its cleartext was zeroed")
+	}
+}
+
+func TestHDDeriveProducesIndependentlyUnlockableChild(t *testing.T) {
+	unlocked, _ := unlockedTestWallet(t)
+
+	childPassphrase := []byte("child passphrase")
+	child, err := HDDerive(unlocked, 0, childPassphrase)
+	if err != nil {
+		t.Fatalf("HDDerive: %v", err)
+	}
+
+	if _, err := Unlock(child, childPassphrase); err != nil {
+		t.Fatalf("Unlock derived child: %v", err)
+	}
+}